@@ -0,0 +1,41 @@
+// Command dns-incluster-server runs the authoritative nameserver that
+// answers DNS queries from the dns-records ConfigMap written by the
+// incluster dns.Provider. It is the image run by the Deployment built in
+// pkg/dns/incluster.Deployment.
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/openshift/cluster-ingress-operator/pkg/dns/incluster/server"
+	logf "github.com/openshift/cluster-ingress-operator/pkg/log"
+)
+
+var log = logf.Logger.WithName("dns_incluster_server")
+
+func main() {
+	recordsFile := flag.String("records-file", "/var/run/dns-records/records.json", "path to the mounted dns-records ConfigMap file")
+	listenAddr := flag.String("listen-addr", ":5353", "address to serve DNS queries on, for both UDP and TCP")
+	flag.Parse()
+
+	s := server.NewServer(server.Config{
+		RecordsFile: *recordsFile,
+		ListenAddr:  *listenAddr,
+	})
+
+	stop := make(chan struct{})
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sig
+		close(stop)
+	}()
+
+	if err := s.Run(stop); err != nil {
+		log.Error(err, "dns-incluster-server exited with error")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,24 @@
+package v1alpha1
+
+// IPFamilyPolicyType represents the dual-stack-ness requested for a
+// ClusterIngress's router, mirroring the semantics of the IPFamilyPolicy
+// field on a Kubernetes Service. It is surfaced as ClusterIngressSpec's new
+// IPFamilyPolicy field so that desiredRouterDeployment can configure the
+// router's listen addresses for IPv6-only and dual-stack clusters instead
+// of assuming IPv4.
+type IPFamilyPolicyType string
+
+const (
+	// IPFamilyPolicyIPv4 serves single-stack IPv4 only.
+	IPFamilyPolicyIPv4 IPFamilyPolicyType = "IPv4"
+	// IPFamilyPolicyIPv6 serves single-stack IPv6 only.
+	IPFamilyPolicyIPv6 IPFamilyPolicyType = "IPv6"
+	// IPFamilyPolicyPreferDualStack configures the router to bind both
+	// families. The operator does not check which families the cluster
+	// supports.
+	IPFamilyPolicyPreferDualStack IPFamilyPolicyType = "PreferDualStack"
+	// IPFamilyPolicyRequireDualStack configures the router exactly as
+	// PreferDualStack does. The operator does not check that both families
+	// are available, and does not fail when they are not.
+	IPFamilyPolicyRequireDualStack IPFamilyPolicyType = "RequireDualStack"
+)
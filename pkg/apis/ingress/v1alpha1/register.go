@@ -0,0 +1,35 @@
+// Package v1alpha1 contains the ClusterIngress API, the legacy install-time
+// configuration CRD that the router deployment and secret observer code in
+// pkg/operator/controller build on.
+// +k8s:deepcopy-gen=package,register
+// +groupName=ingress.openshift.io
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the API group this package's types belong to.
+const GroupName = "ingress.openshift.io"
+
+// SchemeGroupVersion is the group version used to register these types.
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+
+// SchemeBuilder collects the AddToScheme funcs for this package so callers
+// can register it alongside the other API groups their manager needs.
+var (
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds the types in this package to a scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&ClusterIngress{},
+		&ClusterIngressList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
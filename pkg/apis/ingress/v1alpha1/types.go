@@ -0,0 +1,117 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterIngress describes a managed ingress point, including the router
+// deployment that serves it.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterIngress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterIngressSpec   `json:"spec"`
+	Status ClusterIngressStatus `json:"status,omitempty"`
+}
+
+// ClusterIngressList is a list of ClusterIngress.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterIngressList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterIngress `json:"items"`
+}
+
+// ClusterIngressSpec is the desired state of a ClusterIngress.
+type ClusterIngressSpec struct {
+	// IngressDomain is the domain routes admitted by this ClusterIngress
+	// are served under.
+	IngressDomain *string `json:"ingressDomain,omitempty"`
+	// Replicas is the desired number of router pods.
+	Replicas int32 `json:"replicas,omitempty"`
+	// NamespaceSelector, if set, restricts the set of namespaces whose
+	// routes are exposed by this ClusterIngress's router.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// RouteSelector, if set, restricts the set of routes exposed by this
+	// ClusterIngress's router.
+	RouteSelector *metav1.LabelSelector `json:"routeSelector,omitempty"`
+	// NodePlacement, if set, constrains which nodes the router pods are
+	// scheduled to.
+	NodePlacement *NodePlacement `json:"nodePlacement,omitempty"`
+	// DefaultCertificateSecret is the name of a secret, in the router's
+	// namespace, containing the default TLS certificate served by the
+	// router. If unset, a generated self-signed certificate is used.
+	DefaultCertificateSecret *string `json:"defaultCertificateSecret,omitempty"`
+	// IPFamilyPolicy controls the dual-stack-ness of the router
+	// deployment's listen addresses, through the ROUTER_IP_V4_V6_MODE and
+	// ROUTER_LISTEN_ADDR env vars, using the value names of a Kubernetes
+	// Service's IPFamilyPolicy. It takes effect only when
+	// status.highAvailability.type is UserDefined, and it does not change
+	// the probes. If empty, the router is configured for single-stack IPv4.
+	IPFamilyPolicy IPFamilyPolicyType `json:"ipFamilyPolicy,omitempty"`
+}
+
+// NodePlacement describes node scheduling constraints for router pods.
+type NodePlacement struct {
+	// NodeSelector, if set, is applied to the router deployment's pod
+	// template as a node selector.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+}
+
+// ClusterIngressHAType describes how a ClusterIngress's router achieves
+// high availability.
+type ClusterIngressHAType string
+
+const (
+	// CloudClusterIngressHA indicates the router is fronted by a cloud
+	// load balancer.
+	CloudClusterIngressHA ClusterIngressHAType = "Cloud"
+	// UserDefinedClusterIngressHA indicates the router uses host
+	// networking and relies on a user-provided HA solution in front of it.
+	UserDefinedClusterIngressHA ClusterIngressHAType = "UserDefined"
+)
+
+// ClusterIngressHighAvailability describes the high availability strategy
+// in effect for a ClusterIngress.
+type ClusterIngressHighAvailability struct {
+	// Type is the kind of HA solution in use.
+	Type ClusterIngressHAType `json:"type,omitempty"`
+}
+
+// ClusterIngressConditionType is a type of condition a ClusterIngress can
+// report in its status.
+type ClusterIngressConditionType string
+
+const (
+	// ClusterIngressDegraded indicates the ClusterIngress's router cannot
+	// be safely deployed in its current configuration, e.g. because a
+	// secret it depends on does not contain a usable TLS keypair.
+	ClusterIngressDegraded ClusterIngressConditionType = "Degraded"
+)
+
+// ClusterIngressCondition is a single observed condition of a
+// ClusterIngress.
+type ClusterIngressCondition struct {
+	Type               ClusterIngressConditionType `json:"type"`
+	Status             corev1.ConditionStatus      `json:"status"`
+	Reason             string                      `json:"reason,omitempty"`
+	Message            string                      `json:"message,omitempty"`
+	LastTransitionTime metav1.Time                 `json:"lastTransitionTime,omitempty"`
+}
+
+// ClusterIngressStatus is the observed state of a ClusterIngress.
+type ClusterIngressStatus struct {
+	// IngressDomain is the domain actually in effect for this
+	// ClusterIngress's router.
+	IngressDomain string `json:"ingressDomain,omitempty"`
+	// HighAvailability describes the HA strategy actually in effect.
+	HighAvailability ClusterIngressHighAvailability `json:"highAvailability,omitempty"`
+	// Conditions is the list of observed conditions for this
+	// ClusterIngress.
+	Conditions []ClusterIngressCondition `json:"conditions,omitempty"`
+}
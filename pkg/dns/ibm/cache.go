@@ -0,0 +1,96 @@
+package ibm
+
+import (
+	"sync"
+	"time"
+)
+
+// recordCacheKey identifies a single CIS DNS record by the dimensions the
+// provider reconciles on.
+type recordCacheKey struct {
+	zone       string
+	recordType string
+	name       string
+	target     string
+}
+
+// recordCacheEntry is what the provider remembers about a CIS record it has
+// previously seen and verified, so a reconcile that hasn't drifted can skip
+// the list/update calls entirely.
+type recordCacheEntry struct {
+	cisRecordID  string
+	ttl          int64
+	lastVerified time.Time
+}
+
+// nameTypeGroup identifies the (zone, type, name) a set of cached targets
+// belong to, i.e. the scope of a single ListAllDnsRecords call.
+type nameTypeGroup struct {
+	zone       string
+	recordType string
+	name       string
+}
+
+// recordCache is a concurrency-safe cache of recently-verified CIS records,
+// keyed by (zone, type, name, target). It is shared across the provider's
+// concurrent per-target workers, so all access goes through mu.
+type recordCache struct {
+	mu        sync.RWMutex
+	entries   map[recordCacheKey]recordCacheEntry
+	freshness time.Duration
+}
+
+func newRecordCache(freshness time.Duration) *recordCache {
+	return &recordCache{
+		entries:   make(map[recordCacheKey]recordCacheEntry),
+		freshness: freshness,
+	}
+}
+
+// fresh returns the cached entry for key, and true only if it exists, its
+// TTL matches wantTTL, and it was verified within the freshness window.
+func (c *recordCache) fresh(key recordCacheKey, wantTTL int64) (recordCacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[key]
+	if !ok || entry.ttl != wantTTL || time.Since(entry.lastVerified) > c.freshness {
+		return recordCacheEntry{}, false
+	}
+	return entry, true
+}
+
+// lookup returns the cached entry for key regardless of freshness, for
+// callers (e.g. Delete) that only need the CIS record ID and are willing to
+// tolerate a stale-but-still-correct ID.
+func (c *recordCache) lookup(key recordCacheKey) (recordCacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *recordCache) set(key recordCacheKey, entry recordCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
+
+func (c *recordCache) invalidate(key recordCacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// groupByNameType buckets every cached key by the (zone, type, name) that a
+// single ListAllDnsRecords call would cover, for the background
+// revalidation loop.
+func (c *recordCache) groupByNameType() map[nameTypeGroup][]recordCacheKey {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	groups := map[nameTypeGroup][]recordCacheKey{}
+	for key := range c.entries {
+		group := nameTypeGroup{zone: key.zone, recordType: key.recordType, name: key.name}
+		groups[group] = append(groups[group], key)
+	}
+	return groups
+}
@@ -1,7 +1,9 @@
 package ibm
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 	"time"
@@ -15,6 +17,9 @@ import (
 	iov1 "github.com/openshift/api/operatoringress/v1"
 	logf "github.com/openshift/cluster-ingress-operator/pkg/log"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
 )
 
 var (
@@ -27,10 +32,36 @@ const (
 	// CISCustomEndpointName is the key used to identify the CIS service in ServiceEndpoints
 	CISCustomEndpointName = "cis"
 	defaultCISURL         = "https://api.cis.cloud.ibm.com/"
+
+	// defaultQPS is the default rate, in requests per second, at which the
+	// provider is allowed to call the CIS API across all zones.
+	defaultQPS = 5.0
+	// defaultBurst is the default burst size for the shared rate limiter.
+	defaultBurst = 10
+
+	// maxRetries is the number of additional attempts made for a CIS call
+	// that fails with a retryable (429 or 5xx) status, on top of whatever
+	// retries the SDK's own EnableRetries performs at the transport level.
+	maxRetries = 3
+	// retryBaseDelay is the initial delay before the first retry; each
+	// subsequent retry doubles it.
+	retryBaseDelay = 500 * time.Millisecond
+
+	// defaultCacheFreshness is how long a recordCache entry may be relied
+	// on, without re-verifying it against the CIS API, before it is
+	// treated as a cache miss.
+	defaultCacheFreshness = 10 * time.Minute
 )
 
 type Provider struct {
 	dnsServices map[string]dnsclient.DnsClient
+	// limiter is shared across all zones so the aggregate call rate to the
+	// CIS API stays within Config.QPS/Config.Burst regardless of how many
+	// zones or targets are being reconciled concurrently.
+	limiter *rate.Limiter
+	// cache holds recently-verified CIS record IDs so that a reconcile of
+	// an unchanged record can skip the list/update calls entirely.
+	cache *recordCache
 }
 
 // ServiceEndpoint stores the configuration of a custom url to
@@ -53,6 +84,16 @@ type Config struct {
 	Zones     []string
 	// ServiceEndpoints is the list of Custom API endpoints to use for Provider clients.
 	ServiceEndpoints []ServiceEndpoint
+	// QPS is the maximum number of CIS API calls per second the provider
+	// will make across all zones. Defaults to defaultQPS if unset.
+	QPS float64
+	// Burst is the maximum burst size for QPS. Defaults to defaultBurst if
+	// unset.
+	Burst int
+	// CacheFreshness is how long a cached CIS record may be relied on
+	// before it must be re-verified against the CIS API. Defaults to
+	// defaultCacheFreshness if unset.
+	CacheFreshness time.Duration
 }
 
 func NewProvider(config Config) (*Provider, error) {
@@ -62,9 +103,25 @@ func NewProvider(config Config) (*Provider, error) {
 	authenticator := &core.IamAuthenticator{
 		ApiKey: config.APIKey,
 	}
-	provider := &Provider{}
 
-	provider.dnsServices = make(map[string]dnsclient.DnsClient)
+	qps := config.QPS
+	if qps <= 0 {
+		qps = defaultQPS
+	}
+	burst := config.Burst
+	if burst <= 0 {
+		burst = defaultBurst
+	}
+	cacheFreshness := config.CacheFreshness
+	if cacheFreshness <= 0 {
+		cacheFreshness = defaultCacheFreshness
+	}
+
+	provider := &Provider{
+		dnsServices: make(map[string]dnsclient.DnsClient),
+		limiter:     rate.NewLimiter(rate.Limit(qps), burst),
+		cache:       newRecordCache(cacheFreshness),
+	}
 
 	for _, zone := range config.Zones {
 		options := &dnsrecordsv1.DnsRecordsV1Options{
@@ -87,21 +144,86 @@ func NewProvider(config Config) (*Provider, error) {
 	if err := validateDNSServices(provider); err != nil {
 		return nil, fmt.Errorf("failed to validate ibm dns services: %w", err)
 	}
+
+	go provider.runCacheRevalidation()
+
 	return provider, nil
 }
 
+// runCacheRevalidation periodically re-verifies every cached record against
+// the CIS API so that drift from an out-of-band change (a record edited or
+// removed directly in the CIS console) is eventually corrected even if the
+// operator doesn't happen to reconcile that DNSRecord again soon.
+func (p *Provider) runCacheRevalidation() {
+	ticker := time.NewTicker(p.cache.freshness)
+	defer ticker.Stop()
+	for range ticker.C {
+		p.revalidateCache()
+	}
+}
+
+func (p *Provider) revalidateCache() {
+	for group, keys := range p.cache.groupByNameType() {
+		dnsService, ok := p.dnsServices[group.zone]
+		if !ok {
+			continue
+		}
+		existing, err := p.listRecords(dnsService, group.recordType, group.name)
+		if err != nil {
+			log.Info("cache revalidation: failed to list dns records, will retry next pass", "zone", group.zone, "name", group.name, "error", err.Error())
+			continue
+		}
+		byContent := make(map[string]dnsrecordsv1.DnsrecordDetails, len(existing))
+		for _, rec := range existing {
+			if rec.Content != nil {
+				byContent[*rec.Content] = rec
+			}
+		}
+		for _, key := range keys {
+			rec, ok := byContent[key.target]
+			if !ok || rec.ID == nil {
+				p.cache.invalidate(key)
+				continue
+			}
+			var ttl int64
+			if rec.TTL != nil {
+				ttl = *rec.TTL
+			}
+			p.cache.set(key, recordCacheEntry{cisRecordID: *rec.ID, ttl: ttl, lastVerified: time.Now()})
+		}
+	}
+}
+
 // validateDNSServices validates that provider clients can communicate with
-// associated API endpoints by having each client make a get DNS records call.
+// associated API endpoints by having each client make a get DNS records
+// call. Zones are validated concurrently, rate-limited by the provider's
+// shared limiter, and their errors are aggregated so one unreachable zone
+// doesn't prevent reporting problems with the others.
 func validateDNSServices(provider *Provider) error {
-	var errs []error
-	maxItems := int64(1)
-	for _, dnsService := range provider.dnsServices {
-		opt := dnsService.NewListAllDnsRecordsOptions()
-		opt.PerPage = &maxItems
-		if _, _, err := dnsService.ListAllDnsRecords(opt); err != nil {
-			errs = append(errs, fmt.Errorf("failed to get dns records: %w", err))
-		}
+	var g errgroup.Group
+	errs := make([]error, len(provider.dnsServices))
+	i := 0
+	for zone, dnsService := range provider.dnsServices {
+		zone, dnsService, idx := zone, dnsService, i
+		i++
+		g.Go(func() error {
+			if err := provider.limiter.Wait(context.Background()); err != nil {
+				errs[idx] = fmt.Errorf("zone %s: rate limiter: %w", zone, err)
+				return nil
+			}
+			maxItems := int64(1)
+			opt := dnsService.NewListAllDnsRecordsOptions()
+			opt.PerPage = &maxItems
+			if _, _, err := callWithRetry(func() (*core.DetailedResponse, error) {
+				_, resp, err := dnsService.ListAllDnsRecords(opt)
+				return resp, err
+			}); err != nil {
+				errs[idx] = fmt.Errorf("zone %s: failed to get dns records: %w", zone, err)
+			}
+			return nil
+		})
 	}
+	_ = g.Wait()
 	return kerrors.NewAggregate(errs)
 }
 
@@ -113,6 +235,11 @@ func (p *Provider) Replace(record *iov1.DNSRecord, zone configv1.DNSZone) error
 	return p.createOrUpdateDNSRecord(record, zone)
 }
 
+// Delete removes the CIS records matching record's name, type, and targets
+// in the given zone. Targets with a cached CIS record ID are deleted
+// directly; the remaining targets are resolved with a single list call.
+// Deletes are issued concurrently, rate-limited by the provider's shared
+// limiter, with errors aggregated across targets.
 func (p *Provider) Delete(record *iov1.DNSRecord, zone configv1.DNSZone) error {
 	if err := validateInputDNSData(record, zone); err != nil {
 		return fmt.Errorf("delete: invalid dns input data: %w", err)
@@ -121,40 +248,96 @@ func (p *Provider) Delete(record *iov1.DNSRecord, zone configv1.DNSZone) error {
 	if !ok {
 		return fmt.Errorf("delete: unknown zone: %v", zone.ID)
 	}
-	opt := dnsService.NewListAllDnsRecordsOptions()
-	opt.SetType(string(record.Spec.RecordType))
-	opt.SetName(record.Spec.DNSName)
+	recordType := string(record.Spec.RecordType)
+	name := dnsNameOf(record)
+
+	idsToDelete := make(map[string]recordCacheKey, len(record.Spec.Targets))
+	var uncached []string
 	for _, target := range record.Spec.Targets {
-		opt.SetContent(target)
-		result, response, err := dnsService.ListAllDnsRecords(opt)
+		key := recordCacheKey{zone: zone.ID, recordType: recordType, name: name, target: target}
+		if entry, ok := p.cache.lookup(key); ok {
+			idsToDelete[entry.cisRecordID] = key
+		} else {
+			uncached = append(uncached, target)
+		}
+	}
+
+	if len(uncached) > 0 {
+		existing, err := p.listRecords(dnsService, recordType, name)
 		if err != nil {
-			if response != nil && response.StatusCode != http.StatusNotFound {
-				return fmt.Errorf("delete: failed to list the dns record: %w", err)
-			}
-			continue
+			return fmt.Errorf("delete: failed to list the dns record: %w", err)
 		}
-		if result == nil || result.Result == nil {
-			return fmt.Errorf("delete: invalid result")
+		desired := make(map[string]bool, len(uncached))
+		for _, target := range uncached {
+			desired[target] = true
 		}
-		for _, resultData := range result.Result {
-			if resultData.ID == nil {
-				return fmt.Errorf("delete: record id is nil")
+		for _, rec := range existing {
+			if rec.Content != nil && desired[*rec.Content] && rec.ID != nil {
+				idsToDelete[*rec.ID] = recordCacheKey{zone: zone.ID, recordType: recordType, name: name, target: *rec.Content}
 			}
-			delOpt := dnsService.NewDeleteDnsRecordOptions(*resultData.ID)
-			_, delResponse, err := dnsService.DeleteDnsRecord(delOpt)
+		}
+	}
+
+	type deletion struct {
+		id  string
+		key recordCacheKey
+	}
+	deletions := make([]deletion, 0, len(idsToDelete))
+	for id, key := range idsToDelete {
+		deletions = append(deletions, deletion{id: id, key: key})
+	}
+
+	var g errgroup.Group
+	errs := make([]error, len(deletions))
+	for i, d := range deletions {
+		i, d := i, d
+		g.Go(func() error {
+			if err := p.limiter.Wait(context.Background()); err != nil {
+				errs[i] = err
+				return nil
+			}
+			delOpt := dnsService.NewDeleteDnsRecordOptions(d.id)
+			delResponse, err := callWithRetry(func() (*core.DetailedResponse, error) {
+				_, resp, err := dnsService.DeleteDnsRecord(delOpt)
+				return resp, err
+			})
 			if err != nil {
-				if delResponse != nil && delResponse.StatusCode != http.StatusNotFound {
-					return fmt.Errorf("delete: failed to delete the dns record: %w", err)
+				// A 404 means the record is already gone -- from a prior
+				// partially-successful Delete, or an out-of-band change in
+				// CIS -- which a cached cisRecordID can make look present
+				// when it no longer is. Treat that the same as a
+				// successful delete so Delete stays idempotent; any other
+				// error is a real failure.
+				if delResponse != nil && delResponse.StatusCode == http.StatusNotFound {
+					p.cache.invalidate(d.key)
+					log.Info("dns record already absent", "record", record.Spec, "zone", zone, "id", d.id)
+					return nil
 				}
+				p.cache.invalidate(d.key)
+				errs[i] = fmt.Errorf("failed to delete dns record %s: %w", d.id, err)
+				return nil
 			}
-			if delResponse != nil && delResponse.StatusCode != http.StatusNotFound {
-				log.Info("deleted DNS record", "record", record.Spec, "zone", zone, "target", target)
-			}
-		}
+			p.cache.invalidate(d.key)
+			log.Info("deleted DNS record", "record", record.Spec, "zone", zone, "id", d.id)
+			return nil
+		})
 	}
-	return nil
+	_ = g.Wait()
+	return kerrors.NewAggregate(errs)
 }
 
+// createOrUpdateDNSRecord reconciles record in zone, computing the minimal
+// create/update/delete set. If every target has a cache entry that is
+// fresh and matches the desired TTL, it returns immediately without
+// calling the CIS API at all. Otherwise it lists the existing CIS records
+// for the record's (type, name) once, diffs the returned target set
+// against record.Spec.Targets locally, and then creates each target that
+// has no CIS record, updates each target that has one (whether or not its
+// TTL already matches), and deletes any existing CIS record
+// whose content has fallen out of record.Spec.Targets, concurrently and
+// rate-limited by the provider's shared limiter, refreshing the cache as
+// it goes. Per-target errors are aggregated so that one failing target
+// doesn't mask the others succeeding.
 func (p *Provider) createOrUpdateDNSRecord(record *iov1.DNSRecord, zone configv1.DNSZone) error {
 	if err := validateInputDNSData(record, zone); err != nil {
 		return fmt.Errorf("createOrUpdateDNSRecord: invalid dns input data: %w", err)
@@ -170,49 +353,201 @@ func (p *Provider) createOrUpdateDNSRecord(record *iov1.DNSRecord, zone configv1
 		record.Spec.RecordTTL = defaultCISRecordTTL
 	}
 
-	listOpt := dnsService.NewListAllDnsRecordsOptions()
-	listOpt.SetType(string(record.Spec.RecordType))
 	// Some dns records (e.g. wildcard record) have an ending "." character in the DNSName
 	DNSName := strings.TrimSuffix(record.Spec.DNSName, ".")
-	listOpt.SetName(DNSName)
+	recordType := string(record.Spec.RecordType)
+
+	allFresh := len(record.Spec.Targets) > 0
 	for _, target := range record.Spec.Targets {
-		listOpt.SetContent(target)
-		result, response, err := dnsService.ListAllDnsRecords(listOpt)
-		if err != nil {
-			if response != nil && response.StatusCode != http.StatusNotFound {
-				return fmt.Errorf("createOrUpdateDNSRecord: failed to list the dns record: %w", err)
-			}
-			continue
+		key := recordCacheKey{zone: zone.ID, recordType: recordType, name: DNSName, target: target}
+		if _, ok := p.cache.fresh(key, record.Spec.RecordTTL); !ok {
+			allFresh = false
+			break
 		}
-		if result == nil || result.Result == nil {
-			return fmt.Errorf("createOrUpdateDNSRecord: invalid result")
+	}
+	if allFresh {
+		return nil
+	}
+
+	existing, err := p.listRecords(dnsService, recordType, DNSName)
+	if err != nil {
+		return fmt.Errorf("createOrUpdateDNSRecord: failed to list the dns record: %w", err)
+	}
+
+	existingByContent := make(map[string]string, len(existing))
+	for _, rec := range existing {
+		if rec.Content != nil && rec.ID != nil {
+			existingByContent[*rec.Content] = *rec.ID
 		}
-		if len(result.Result) == 0 {
-			createOpt := dnsService.NewCreateDnsRecordOptions()
-			createOpt.SetName(record.Spec.DNSName)
-			createOpt.SetType(string(record.Spec.RecordType))
-			createOpt.SetContent(target)
-			createOpt.SetTTL(record.Spec.RecordTTL)
-			_, _, err := dnsService.CreateDnsRecord(createOpt)
+	}
+
+	desiredTargets := make(map[string]bool, len(record.Spec.Targets))
+	for _, target := range record.Spec.Targets {
+		desiredTargets[target] = true
+	}
+
+	type task func() error
+	var tasks []task
+
+	// A CIS record whose content is no longer in record.Spec.Targets (the
+	// target list shrank since the last reconcile) is stale and must be
+	// removed, the same way Delete() removes records for a target list
+	// that's gone away entirely.
+	for _, rec := range existing {
+		if rec.Content == nil || rec.ID == nil || desiredTargets[*rec.Content] {
+			continue
+		}
+		id := *rec.ID
+		target := *rec.Content
+		key := recordCacheKey{zone: zone.ID, recordType: recordType, name: DNSName, target: target}
+		tasks = append(tasks, func() error {
+			delOpt := dnsService.NewDeleteDnsRecordOptions(id)
+			delResponse, err := callWithRetry(func() (*core.DetailedResponse, error) {
+				_, resp, err := dnsService.DeleteDnsRecord(delOpt)
+				return resp, err
+			})
 			if err != nil {
-				return fmt.Errorf("createOrUpdateDNSRecord: failed to create the dns record: %w", err)
+				if delResponse != nil && delResponse.StatusCode == http.StatusNotFound {
+					p.cache.invalidate(key)
+					return nil
+				}
+				return fmt.Errorf("failed to delete stale dns record %s: %w", id, err)
 			}
-			log.Info("created DNS record", "record", record.Spec, "zone", zone, "target", target)
+			p.cache.invalidate(key)
+			log.Info("deleted stale DNS record", "record", record.Spec, "zone", zone, "target", target)
+			return nil
+		})
+	}
+	for _, target := range record.Spec.Targets {
+		target := target
+		key := recordCacheKey{zone: zone.ID, recordType: recordType, name: DNSName, target: target}
+		if id, ok := existingByContent[target]; ok {
+			id := id
+			tasks = append(tasks, func() error {
+				updateOpt := dnsService.NewUpdateDnsRecordOptions(id)
+				updateOpt.SetName(record.Spec.DNSName)
+				updateOpt.SetType(string(record.Spec.RecordType))
+				updateOpt.SetContent(target)
+				updateOpt.SetTTL(record.Spec.RecordTTL)
+				if _, err := callWithRetry(func() (*core.DetailedResponse, error) {
+					_, resp, err := dnsService.UpdateDnsRecord(updateOpt)
+					return resp, err
+				}); err != nil {
+					p.cache.invalidate(key)
+					return fmt.Errorf("failed to update the dns record: %w", err)
+				}
+				p.cache.set(key, recordCacheEntry{cisRecordID: id, ttl: record.Spec.RecordTTL, lastVerified: time.Now()})
+				log.Info("updated DNS record", "record", record.Spec, "zone", zone, "target", target)
+				return nil
+			})
 		} else {
-			updateOpt := dnsService.NewUpdateDnsRecordOptions(*result.Result[0].ID)
-			updateOpt.SetName(record.Spec.DNSName)
-			updateOpt.SetType(string(record.Spec.RecordType))
-			updateOpt.SetContent(target)
-			updateOpt.SetTTL(record.Spec.RecordTTL)
-			_, _, err := dnsService.UpdateDnsRecord(updateOpt)
-			if err != nil {
-				return fmt.Errorf("createOrUpdateDNSRecord: failed to update the dns record: %w", err)
+			tasks = append(tasks, func() error {
+				createOpt := dnsService.NewCreateDnsRecordOptions()
+				createOpt.SetName(record.Spec.DNSName)
+				createOpt.SetType(string(record.Spec.RecordType))
+				createOpt.SetContent(target)
+				createOpt.SetTTL(record.Spec.RecordTTL)
+				var result *dnsrecordsv1.DnsrecordResp
+				_, err := callWithRetry(func() (*core.DetailedResponse, error) {
+					res, resp, err := dnsService.CreateDnsRecord(createOpt)
+					result = res
+					return resp, err
+				})
+				if err != nil {
+					p.cache.invalidate(key)
+					return fmt.Errorf("failed to create the dns record: %w", err)
+				}
+				if result != nil && result.Result != nil && result.Result.ID != nil {
+					p.cache.set(key, recordCacheEntry{cisRecordID: *result.Result.ID, ttl: record.Spec.RecordTTL, lastVerified: time.Now()})
+				}
+				log.Info("created DNS record", "record", record.Spec, "zone", zone, "target", target)
+				return nil
+			})
+		}
+	}
+
+	var g errgroup.Group
+	errs := make([]error, len(tasks))
+	for i, t := range tasks {
+		i, t := i, t
+		g.Go(func() error {
+			if err := p.limiter.Wait(context.Background()); err != nil {
+				errs[i] = err
+				return nil
 			}
-			log.Info("updated DNS record", "record", record.Spec, "zone", zone, "target", target)
+			errs[i] = t()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return kerrors.NewAggregate(errs)
+}
+
+// listRecords issues a single ListAllDnsRecords call for the given type and
+// name (with no content filter) so callers can diff the full target set
+// locally instead of listing once per target.
+func (p *Provider) listRecords(dnsService dnsclient.DnsClient, recordType, name string) ([]dnsrecordsv1.DnsrecordDetails, error) {
+	listOpt := dnsService.NewListAllDnsRecordsOptions()
+	listOpt.SetType(recordType)
+	listOpt.SetName(name)
+	if err := p.limiter.Wait(context.Background()); err != nil {
+		return nil, err
+	}
+	var result *dnsrecordsv1.ListDnsrecordsResp
+	response, err := callWithRetry(func() (*core.DetailedResponse, error) {
+		res, resp, err := dnsService.ListAllDnsRecords(listOpt)
+		result = res
+		return resp, err
+	})
+	if err != nil {
+		// A 404 means there are simply no records matching type/name yet,
+		// which callers treat the same as an empty list. Any other error
+		// (auth, transport, 429/5xx that exhausted retries, etc.) must be
+		// propagated: silently returning an empty list here would make
+		// createOrUpdateDNSRecord re-create records that already exist,
+		// and would make Delete believe there is nothing left to delete.
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil, nil
 		}
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("invalid result")
 	}
+	return result.Result, nil
+}
+
+// callWithRetry runs fn, retrying with exponential backoff when the
+// response indicates a rate limit (429) or a server error (5xx). It is a
+// belt-and-suspenders layer on top of the SDK's own EnableRetries, which
+// only covers transport-level retries for a fixed window.
+func callWithRetry(fn func() (*core.DetailedResponse, error)) (*core.DetailedResponse, error) {
+	var resp *core.DetailedResponse
+	var err error
+	delay := retryBaseDelay
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		resp, err = fn()
+		if err == nil {
+			return resp, nil
+		}
+		if resp == nil || !isRetryableStatus(resp.StatusCode) {
+			return resp, err
+		}
+		if attempt == maxRetries {
+			break
+		}
+		time.Sleep(delay)
+		delay = time.Duration(math.Min(float64(delay*2), float64(30*time.Second)))
+	}
+	return resp, err
+}
+
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
 
-	return nil
+func dnsNameOf(record *iov1.DNSRecord) string {
+	return strings.TrimSuffix(record.Spec.DNSName, ".")
 }
 
 func validateInputDNSData(record *iov1.DNSRecord, zone configv1.DNSZone) error {
@@ -0,0 +1,244 @@
+// Package incluster implements a dns.Provider that is backed by an
+// authoritative nameserver running inside the cluster instead of a cloud DNS
+// service. It is intended for disconnected or on-prem deployments (for
+// example IBM Power VS installs that cannot reach CIS) where the operator
+// still needs to satisfy the DNSRecord flow.
+//
+// The Provider in this file only maintains the records ConfigMap; the
+// nameserver that actually answers queries is cmd/dns-incluster-server,
+// built on the server subpackage, and is deployed with the Deployment,
+// Service, and RBAC built in manifests.go. UseForPlatform is the predicate
+// the operator's DNS provider selection should consult to decide when to
+// construct this provider instead of a cloud one.
+package incluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	configv1 "github.com/openshift/api/config/v1"
+	iov1 "github.com/openshift/api/operatoringress/v1"
+	"github.com/openshift/cluster-ingress-operator/pkg/dns"
+	logf "github.com/openshift/cluster-ingress-operator/pkg/log"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kutilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	_   dns.Provider = &Provider{}
+	log              = logf.Logger.WithName("dns")
+)
+
+const (
+	// RecordsConfigMapName is the name of the ConfigMap that holds the zone
+	// data served by the in-cluster nameserver.
+	RecordsConfigMapName = "dns-records"
+	// RecordsConfigMapNamespace is the namespace the records ConfigMap and
+	// the nameserver deployment live in.
+	RecordsConfigMapNamespace = "openshift-ingress"
+	// recordsDataKey is the key within the records ConfigMap whose value is
+	// the serialized zone data. The nameserver mounts the ConfigMap as a
+	// file under this name and watches it for changes.
+	recordsDataKey = "records.json"
+	// generationAnnotation is incremented on every write to the records
+	// ConfigMap, so each write shows up as a new value on the ConfigMap's
+	// metadata. The nameserver does not read it: it only sees the mounted
+	// data file and re-parses that on every change event. Concurrent
+	// writers are detected by resourceVersion conflicts, not by this value.
+	generationAnnotation = "ingress.operator.openshift.io/dns-generation"
+)
+
+// Config is the necessary input to configure the provider.
+type Config struct {
+	// Client is used to read and write the records ConfigMap.
+	Client client.Client
+}
+
+// Provider is a dns.Provider that serves DNSRecord CRs from an in-cluster
+// authoritative nameserver rather than a cloud DNS service. Ensure, Replace,
+// and Delete only maintain the records ConfigMap; the nameserver itself
+// (see the server subpackage) watches that ConfigMap and answers queries
+// from an in-memory zone tree.
+type Provider struct {
+	config Config
+}
+
+// zoneRecord is the value persisted for a single (name, type) pair in the
+// records ConfigMap.
+type zoneRecord struct {
+	Targets []string `json:"targets"`
+	TTL     int64    `json:"ttl"`
+}
+
+// zoneData is the shape of the serialized records ConfigMap data, keyed by
+// FQDN and then by record type (e.g. "A", "AAAA", "CNAME").
+type zoneData map[string]map[string]zoneRecord
+
+// NewProvider returns a new in-cluster dns.Provider.
+func NewProvider(config Config) (*Provider, error) {
+	if config.Client == nil {
+		return nil, fmt.Errorf("missing client")
+	}
+	return &Provider{config: config}, nil
+}
+
+func (p *Provider) Ensure(record *iov1.DNSRecord, zone configv1.DNSZone) error {
+	return p.upsertRecord(record)
+}
+
+func (p *Provider) Replace(record *iov1.DNSRecord, zone configv1.DNSZone) error {
+	return p.upsertRecord(record)
+}
+
+func (p *Provider) Delete(record *iov1.DNSRecord, zone configv1.DNSZone) error {
+	if err := validateInputDNSData(record); err != nil {
+		return fmt.Errorf("delete: invalid dns input data: %w", err)
+	}
+	name := dnsName(record)
+	recordType := string(record.Spec.RecordType)
+	err := p.mutateRecords(func(data zoneData) {
+		if _, ok := data[name]; !ok {
+			return
+		}
+		delete(data[name], recordType)
+		if len(data[name]) == 0 {
+			delete(data, name)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("delete: failed to update records configmap: %w", err)
+	}
+	log.Info("deleted DNS record", "record", record.Spec)
+	return nil
+}
+
+func (p *Provider) upsertRecord(record *iov1.DNSRecord) error {
+	if err := validateInputDNSData(record); err != nil {
+		return fmt.Errorf("invalid dns input data: %w", err)
+	}
+	name := dnsName(record)
+	recordType := string(record.Spec.RecordType)
+	err := p.mutateRecords(func(data zoneData) {
+		if data[name] == nil {
+			data[name] = map[string]zoneRecord{}
+		}
+		data[name][recordType] = zoneRecord{
+			Targets: record.Spec.Targets,
+			TTL:     record.Spec.RecordTTL,
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update records configmap: %w", err)
+	}
+	log.Info("updated DNS record", "record", record.Spec)
+	return nil
+}
+
+// mutateRecords reads the records ConfigMap (creating it if it does not yet
+// exist), applies mutate to the decoded zone data, and writes the result
+// back along with a bumped generation annotation. Every DNSRecord in the
+// cluster funnels through this single ConfigMap, so concurrent Ensure/
+// Replace/Delete calls routinely race on its resourceVersion; the
+// read-modify-write is retried on conflict so a losing writer re-reads the
+// latest version and re-applies mutate instead of failing the reconcile.
+func (p *Provider) mutateRecords(mutate func(zoneData)) error {
+	ctx := context.TODO()
+	key := client.ObjectKey{Namespace: RecordsConfigMapNamespace, Name: RecordsConfigMapName}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := &corev1.ConfigMap{}
+		create := false
+		if err := p.config.Client.Get(ctx, key, cm); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return fmt.Errorf("failed to get configmap %s: %w", key, err)
+			}
+			create = true
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+			}
+		}
+
+		data := zoneData{}
+		if raw, ok := cm.Data[recordsDataKey]; ok && len(raw) > 0 {
+			if err := json.Unmarshal([]byte(raw), &data); err != nil {
+				return fmt.Errorf("failed to unmarshal records configmap data: %w", err)
+			}
+		}
+
+		mutate(data)
+
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal records configmap data: %w", err)
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[recordsDataKey] = string(encoded)
+		if cm.Annotations == nil {
+			cm.Annotations = map[string]string{}
+		}
+		cm.Annotations[generationAnnotation] = strconv.FormatInt(nextGeneration(cm.Annotations[generationAnnotation]), 10)
+
+		if create {
+			if err := p.config.Client.Create(ctx, cm); err != nil {
+				return fmt.Errorf("failed to create configmap %s: %w", key, err)
+			}
+			return nil
+		}
+		if err := p.config.Client.Update(ctx, cm); err != nil {
+			return fmt.Errorf("failed to update configmap %s: %w", key, err)
+		}
+		return nil
+	})
+}
+
+// nextGeneration parses the current generation annotation value, defaulting
+// to 0 if it is absent or malformed, and returns the next value.
+func nextGeneration(current string) int64 {
+	gen, err := strconv.ParseInt(current, 10, 64)
+	if err != nil {
+		gen = 0
+	}
+	return gen + 1
+}
+
+// dnsName returns the zone tree key for a DNSRecord, normalized to drop any
+// trailing "." so lookups are consistent regardless of how the record was
+// authored.
+func dnsName(record *iov1.DNSRecord) string {
+	name := record.Spec.DNSName
+	if len(name) > 0 && name[len(name)-1] == '.' {
+		name = name[:len(name)-1]
+	}
+	return name
+}
+
+func validateInputDNSData(record *iov1.DNSRecord) error {
+	var errs []error
+	if record == nil {
+		errs = append(errs, fmt.Errorf("validateInputDNSData: dns record is nil"))
+		return kutilerrors.NewAggregate(errs)
+	}
+	if len(record.Spec.DNSName) == 0 {
+		errs = append(errs, fmt.Errorf("validateInputDNSData: dns record name is empty"))
+	}
+	if len(record.Spec.RecordType) == 0 {
+		errs = append(errs, fmt.Errorf("validateInputDNSData: dns record type is empty"))
+	}
+	if len(record.Spec.Targets) == 0 {
+		errs = append(errs, fmt.Errorf("validateInputDNSData: dns record content is empty"))
+	}
+	return kutilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,155 @@
+package incluster
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// DeploymentName is the name of the Deployment and the label value used
+	// to select its pods.
+	DeploymentName = "dns-incluster"
+	// ServiceAccountName is the identity the nameserver Deployment runs as.
+	// It is granted no RBAC of its own: the nameserver only ever reads its
+	// mounted ConfigMap volume, never the API server.
+	ServiceAccountName = "dns-incluster"
+	// dnsPort is the port the nameserver listens on for both UDP and TCP.
+	dnsPort int32 = 5353
+	// recordsMountPath is where the records ConfigMap is mounted into the
+	// nameserver container; must match the --records-file flag passed to
+	// cmd/dns-incluster-server.
+	recordsMountPath = "/var/run/dns-records"
+)
+
+// Deployment returns the Deployment that runs the in-cluster authoritative
+// nameserver (cmd/dns-incluster-server, built on the server subpackage). It
+// mounts the records ConfigMap as a volume so the nameserver's fsnotify loop
+// can watch the file for changes.
+func Deployment(namespace, image string) *appsv1.Deployment {
+	replicas := int32(2)
+	labels := map[string]string{"app": DeploymentName}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DeploymentName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: ServiceAccountName,
+					Containers: []corev1.Container{
+						{
+							Name:  "server",
+							Image: image,
+							Args: []string{
+								fmt.Sprintf("--records-file=%s/%s", recordsMountPath, recordsDataKey),
+								fmt.Sprintf("--listen-addr=:%d", dnsPort),
+							},
+							Ports: []corev1.ContainerPort{
+								{Name: "dns-udp", ContainerPort: dnsPort, Protocol: corev1.ProtocolUDP},
+								{Name: "dns-tcp", ContainerPort: dnsPort, Protocol: corev1.ProtocolTCP},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "records", MountPath: recordsMountPath, ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "records",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{Name: RecordsConfigMapName},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Service exposes the nameserver Deployment's DNS port inside the cluster.
+func Service(namespace string) *corev1.Service {
+	labels := map[string]string{"app": DeploymentName}
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DeploymentName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports: []corev1.ServicePort{
+				{Name: "dns-udp", Port: dnsPort, Protocol: corev1.ProtocolUDP, TargetPort: intstr.FromInt(int(dnsPort))},
+				{Name: "dns-tcp", Port: dnsPort, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt(int(dnsPort))},
+			},
+		},
+	}
+}
+
+// ServiceAccount is the identity the nameserver Deployment runs as.
+func ServiceAccount(namespace string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: ServiceAccountName, Namespace: namespace},
+	}
+}
+
+// Role grants the operator's own service account (not the nameserver pod --
+// see ServiceAccountName's doc comment) permission to manage the records
+// ConfigMap that the Provider writes to. get/update/patch are scoped to
+// RecordsConfigMapName via ResourceNames; list/watch/create apply to all
+// configmaps in the namespace.
+func Role(namespace string) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: DeploymentName, Namespace: namespace},
+		Rules: []rbacv1.PolicyRule{
+			{
+				// get/update/patch are scoped to RecordsConfigMapName via
+				// ResourceNames.
+				APIGroups:     []string{""},
+				Resources:     []string{"configmaps"},
+				ResourceNames: []string{RecordsConfigMapName},
+				Verbs:         []string{"get", "update", "patch"},
+			},
+			{
+				// list/watch/create can't carry a ResourceNames
+				// restriction -- the API server doesn't support scoping
+				// them by name -- so they're granted unscoped. list/watch
+				// can therefore read every configmap in the namespace;
+				// get/update/patch above limit updates of existing
+				// configmaps to RecordsConfigMapName.
+				APIGroups: []string{""},
+				Resources: []string{"configmaps"},
+				Verbs:     []string{"list", "watch", "create"},
+			},
+		},
+	}
+}
+
+// RoleBinding binds Role to operatorServiceAccountName, the operator's own
+// service account -- never ServiceAccountName, which is the nameserver
+// pod's identity and is granted no RBAC of its own.
+func RoleBinding(namespace, operatorServiceAccountName string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: DeploymentName, Namespace: namespace},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     DeploymentName,
+		},
+		Subjects: []rbacv1.Subject{
+			{Kind: "ServiceAccount", Name: operatorServiceAccountName, Namespace: namespace},
+		},
+	}
+}
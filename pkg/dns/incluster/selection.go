@@ -0,0 +1,16 @@
+package incluster
+
+import (
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// UseForPlatform reports whether the in-cluster provider should be used
+// instead of a cloud DNS provider for the given infrastructure platform.
+// It is the predicate newDNSProviderForPlatform consults before its
+// per-cloud cases (the one that picks ibm.NewProvider for an IBM Cloud
+// platform, for example): platforms with no reachable cloud DNS service --
+// bare-metal/None today, and IBM Power VS once CIS reachability can be
+// probed at startup -- should get this provider instead.
+func UseForPlatform(platform configv1.PlatformType) bool {
+	return platform == configv1.NonePlatform
+}
@@ -0,0 +1,255 @@
+// Package server implements the authoritative nameserver that answers
+// queries for the FQDNs recorded in the dns-records ConfigMap managed by
+// the incluster dns.Provider. It is built as a standalone binary that
+// mounts the ConfigMap as a file, watches the file for changes with
+// fsnotify, and swaps its in-memory zone tree atomically whenever the
+// content changes.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/miekg/dns"
+
+	logf "github.com/openshift/cluster-ingress-operator/pkg/log"
+)
+
+var log = logf.Logger.WithName("dns_server")
+
+// recordSet mirrors the shape written by the incluster dns.Provider for a
+// single (name, type) pair.
+type recordSet struct {
+	Targets []string `json:"targets"`
+	TTL     int64    `json:"ttl"`
+}
+
+// zoneData mirrors the shape of the records file, keyed by FQDN and then by
+// record type.
+type zoneData map[string]map[string]recordSet
+
+// Config is the necessary input to run the nameserver.
+type Config struct {
+	// RecordsFile is the path to the mounted records ConfigMap file.
+	RecordsFile string
+	// ListenAddr is the address the server listens on for both UDP and
+	// TCP, e.g. ":5353".
+	ListenAddr string
+}
+
+// Server is an authoritative DNS server that answers A, AAAA, and CNAME
+// queries from an in-memory zone tree loaded from RecordsFile, and
+// REFUSES (rather than NXDOMAIN) anything else so that callers using it as
+// one of several resolvers can fall through to another nameserver.
+type Server struct {
+	config Config
+	// zone holds the current zoneData, swapped atomically on reload so
+	// that in-flight queries never observe a half-written zone.
+	zone atomic.Value
+}
+
+// NewServer returns a Server configured to load records from
+// config.RecordsFile. Call Run to load the initial zone, start watching
+// for changes, and serve queries.
+func NewServer(config Config) *Server {
+	s := &Server{config: config}
+	s.zone.Store(zoneData{})
+	return s
+}
+
+// Run loads the initial zone data, starts the fsnotify watch loop, and
+// serves DNS queries on config.ListenAddr until stop is closed or either
+// listener fails.
+func (s *Server) Run(stop <-chan struct{}) error {
+	if err := s.reload(); err != nil {
+		return fmt.Errorf("failed to load initial zone data: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(s.config.RecordsFile); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", s.config.RecordsFile, err)
+	}
+	go s.watchLoop(watcher, stop)
+
+	mux := dns.NewServeMux()
+	mux.HandleFunc(".", s.handleQuery)
+
+	udpServer := &dns.Server{Addr: s.config.ListenAddr, Net: "udp", Handler: mux}
+	tcpServer := &dns.Server{Addr: s.config.ListenAddr, Net: "tcp", Handler: mux}
+
+	errCh := make(chan error, 2)
+	go func() { errCh <- udpServer.ListenAndServe() }()
+	go func() { errCh <- tcpServer.ListenAndServe() }()
+
+	select {
+	case <-stop:
+		udpServer.Shutdown()
+		tcpServer.Shutdown()
+		return nil
+	case err := <-errCh:
+		udpServer.Shutdown()
+		tcpServer.Shutdown()
+		return err
+	}
+}
+
+// watchLoop reloads the zone whenever the records file is written, and
+// re-adds the watch on rename/remove events since ConfigMap volume mounts
+// are updated by symlink swap rather than in-place write.
+func (s *Server) watchLoop(watcher *fsnotify.Watcher, stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				if err := watcher.Add(s.config.RecordsFile); err != nil {
+					log.Error(err, "failed to re-add watch after rename/remove", "file", s.config.RecordsFile)
+				}
+			}
+			if err := s.reload(); err != nil {
+				log.Error(err, "failed to reload zone data", "file", s.config.RecordsFile)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Error(err, "watcher error", "file", s.config.RecordsFile)
+		}
+	}
+}
+
+// reload reads and parses the records file and atomically swaps it in as
+// the zone tree served by handleQuery.
+func (s *Server) reload() error {
+	raw, err := os.ReadFile(s.config.RecordsFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", s.config.RecordsFile, err)
+	}
+	data := zoneData{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return fmt.Errorf("failed to unmarshal %s: %w", s.config.RecordsFile, err)
+		}
+	}
+	s.zone.Store(data)
+	log.Info("reloaded zone data", "file", s.config.RecordsFile, "names", len(data))
+	return nil
+}
+
+// handleQuery answers A, AAAA, and CNAME queries from the in-memory zone
+// tree. Names that are not configured are REFUSED rather than answered
+// with NXDOMAIN so that a resolver chaining this server with another
+// authoritative or recursive resolver can fall through to it instead of
+// treating the name as definitively absent.
+func (s *Server) handleQuery(w dns.ResponseWriter, r *dns.Msg) {
+	msg := new(dns.Msg)
+	msg.SetReply(r)
+
+	if len(r.Question) != 1 {
+		msg.Rcode = dns.RcodeRefused
+		w.WriteMsg(msg)
+		return
+	}
+	question := r.Question[0]
+	data := s.zone.Load().(zoneData)
+	name := trimTrailingDot(question.Name)
+	types, ok := data[name]
+	if !ok {
+		msg.Rcode = dns.RcodeRefused
+		w.WriteMsg(msg)
+		return
+	}
+
+	var rrs []dns.RR
+	switch question.Qtype {
+	case dns.TypeA:
+		rrs = aRecords(question.Name, types["A"])
+	case dns.TypeAAAA:
+		rrs = aaaaRecords(question.Name, types["AAAA"])
+	case dns.TypeCNAME:
+		rrs = cnameRecords(question.Name, types["CNAME"])
+	default:
+		msg.Rcode = dns.RcodeRefused
+		w.WriteMsg(msg)
+		return
+	}
+	if len(rrs) == 0 {
+		msg.Rcode = dns.RcodeRefused
+		w.WriteMsg(msg)
+		return
+	}
+
+	msg.Authoritative = true
+	msg.Answer = rrs
+	w.WriteMsg(msg)
+}
+
+func aRecords(name string, set recordSet) []dns.RR {
+	var rrs []dns.RR
+	for _, target := range set.Targets {
+		ip := net.ParseIP(target)
+		if ip == nil || ip.To4() == nil {
+			continue
+		}
+		rrs = append(rrs, &dns.A{
+			Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: ttlOrDefault(set.TTL)},
+			A:   ip,
+		})
+	}
+	return rrs
+}
+
+func aaaaRecords(name string, set recordSet) []dns.RR {
+	var rrs []dns.RR
+	for _, target := range set.Targets {
+		ip := net.ParseIP(target)
+		if ip == nil || ip.To4() != nil {
+			continue
+		}
+		rrs = append(rrs, &dns.AAAA{
+			Hdr:  dns.RR_Header{Name: name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: ttlOrDefault(set.TTL)},
+			AAAA: ip,
+		})
+	}
+	return rrs
+}
+
+func cnameRecords(name string, set recordSet) []dns.RR {
+	var rrs []dns.RR
+	for _, target := range set.Targets {
+		rrs = append(rrs, &dns.CNAME{
+			Hdr:    dns.RR_Header{Name: name, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: ttlOrDefault(set.TTL)},
+			Target: dns.Fqdn(target),
+		})
+	}
+	return rrs
+}
+
+func ttlOrDefault(ttl int64) uint32 {
+	if ttl <= 0 {
+		return 120
+	}
+	return uint32(ttl)
+}
+
+func trimTrailingDot(name string) string {
+	if len(name) > 0 && name[len(name)-1] == '.' {
+		return name[:len(name)-1]
+	}
+	return name
+}
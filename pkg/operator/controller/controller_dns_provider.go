@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-ingress-operator/pkg/dns"
+	"github.com/openshift/cluster-ingress-operator/pkg/dns/ibm"
+	"github.com/openshift/cluster-ingress-operator/pkg/dns/incluster"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newDNSProviderForPlatform selects and constructs the dns.Provider to use
+// for the cluster's infrastructure platform. incluster.UseForPlatform gets
+// first say, since a platform with no reachable cloud DNS service (today,
+// bare-metal/None) must use the in-cluster nameserver regardless of what
+// other providers are configured; otherwise the platform's cloud DNS
+// provider is used, ibm.NewProvider for IBM Cloud today.
+func newDNSProviderForPlatform(infraConfig *configv1.Infrastructure, cl client.Client, ibmConfig ibm.Config) (dns.Provider, error) {
+	platform := infraConfig.Status.Platform
+
+	if incluster.UseForPlatform(platform) {
+		return incluster.NewProvider(incluster.Config{Client: cl})
+	}
+
+	switch platform {
+	case configv1.IBMCloudPlatform:
+		return ibm.NewProvider(ibmConfig)
+	default:
+		return nil, fmt.Errorf("no dns.Provider available for platform %q", platform)
+	}
+}
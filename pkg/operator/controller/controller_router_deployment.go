@@ -18,6 +18,14 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 )
 
+// routerCertHashAnnotation records a hash of the secrets a router
+// deployment depends on (the default certificate secret, the metrics certs
+// secret, and the stats secret) on the router deployment's pod
+// template. deploymentConfigChanged diffs this annotation so that rotating
+// a secret's contents in place -- which doesn't change the secret name --
+// still triggers a rolling restart of the router pods.
+const routerCertHashAnnotation = "ingress.operator.openshift.io/cert-hash"
+
 // routerDeploymentName returns the namespaced name for the router deployment.
 func routerDeploymentName(ci *ingressv1alpha1.ClusterIngress) types.NamespacedName {
 	return types.NamespacedName{
@@ -33,6 +41,9 @@ func (r *reconciler) ensureRouterDeployment(ci *ingressv1alpha1.ClusterIngress,
 	if err != nil {
 		return nil, fmt.Errorf("failed to build router deployment: %v", err)
 	}
+	if err := r.observeRouterCertSecrets(ci, desired); err != nil {
+		return nil, fmt.Errorf("failed to observe router cert secrets: %v", err)
+	}
 	current, err := r.currentRouterDeployment(ci)
 	if err != nil {
 		return nil, err
@@ -181,6 +192,20 @@ func desiredRouterDeployment(ci *ingressv1alpha1.ClusterIngress, routerImage str
 		env = append(env, corev1.EnvVar{Name: "ROUTE_LABELS", Value: routeSelector.String()})
 	}
 
+	if ci.Status.HighAvailability.Type == ingressv1alpha1.UserDefinedClusterIngressHA {
+		env = append(env, ipFamilyModeEnvVars(ci.Spec.IPFamilyPolicy)...)
+		// Surface the pod's primary IP (status.podIP) to the router via the
+		// downward API so it has an address of the pod's own family instead
+		// of a literal that collapses to IPv4 on dual-stack or doesn't
+		// exist on IPv6-only nodes. The probes do not read this variable.
+		env = append(env, corev1.EnvVar{
+			Name: "POD_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+			},
+		})
+	}
+
 	deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env, env...)
 
 	deployment.Spec.Template.Spec.Containers[0].Image = routerImage
@@ -190,12 +215,18 @@ func desiredRouterDeployment(ci *ingressv1alpha1.ClusterIngress, routerImage str
 		// the user's HA solution.
 		deployment.Spec.Template.Spec.HostNetwork = true
 
-		// With container networking, probes default to using the pod IP
-		// address.  With host networking, probes default to using the
-		// node IP address.  Using localhost avoids potential routing
-		// problems or firewall restrictions.
-		deployment.Spec.Template.Spec.Containers[0].LivenessProbe.Handler.HTTPGet.Host = "localhost"
-		deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.Handler.HTTPGet.Host = "localhost"
+		// Probes default to using the pod IP address, which with host
+		// networking is the node's primary IP.  Leaving Host empty makes
+		// the kubelet probe that primary IP (status.podIPs[0]), of
+		// whichever family it is, instead of a literal "localhost" that
+		// only ever resolves to IPv4.
+		//
+		// A corev1.Probe targets a single endpoint, so on a dual-stack
+		// router (PreferDualStack/RequireDualStack) the liveness and
+		// readiness probes check only the primary family's listener; the
+		// other family's listener is not probed.
+		deployment.Spec.Template.Spec.Containers[0].LivenessProbe.Handler.HTTPGet.Host = ""
+		deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.Handler.HTTPGet.Host = ""
 	}
 
 	// Fill in the default certificate secret name.
@@ -245,9 +276,18 @@ func (r *reconciler) updateRouterDeployment(current, desired *appsv1.Deployment)
 // deploymentConfigChanged checks if current config matches the expected config
 // for the cluster ingress deployment and if not returns the updated config.
 func deploymentConfigChanged(current, expected *appsv1.Deployment) (bool, *appsv1.Deployment) {
-	// As per an offline conversation, this checks only the secret name
-	// for now but can be updated to a `reflect.DeepEqual` if needed.
+	// As per an offline conversation, this checks only the secret name,
+	// the cert hash annotation, the IP family env vars and probe config,
+	// and replicas for now but can be updated to a `reflect.DeepEqual` if
+	// needed.
+	currentEnv := current.Spec.Template.Spec.Containers[0].Env
+	expectedEnv := expected.Spec.Template.Spec.Containers[0].Env
 	if current.Spec.Template.Spec.Volumes[0].Secret.SecretName == expected.Spec.Template.Spec.Volumes[0].Secret.SecretName &&
+		current.Spec.Template.Annotations[routerCertHashAnnotation] == expected.Spec.Template.Annotations[routerCertHashAnnotation] &&
+		envValue(currentEnv, "ROUTER_IP_V4_V6_MODE") == envValue(expectedEnv, "ROUTER_IP_V4_V6_MODE") &&
+		envValue(currentEnv, "ROUTER_LISTEN_ADDR") == envValue(expectedEnv, "ROUTER_LISTEN_ADDR") &&
+		current.Spec.Template.Spec.Containers[0].LivenessProbe.Handler.HTTPGet.Host == expected.Spec.Template.Spec.Containers[0].LivenessProbe.Handler.HTTPGet.Host &&
+		current.Spec.Template.Spec.Containers[0].ReadinessProbe.Handler.HTTPGet.Host == expected.Spec.Template.Spec.Containers[0].ReadinessProbe.Handler.HTTPGet.Host &&
 		current.Spec.Replicas != nil &&
 		*current.Spec.Replicas == *expected.Spec.Replicas {
 		return false, nil
@@ -255,10 +295,51 @@ func deploymentConfigChanged(current, expected *appsv1.Deployment) (bool, *appsv
 
 	updated := current.DeepCopy()
 	updated.Spec.Template.Spec.Volumes[0].Secret.SecretName = expected.Spec.Template.Spec.Volumes[0].Secret.SecretName
+	if updated.Spec.Template.Annotations == nil {
+		updated.Spec.Template.Annotations = map[string]string{}
+	}
+	updated.Spec.Template.Annotations[routerCertHashAnnotation] = expected.Spec.Template.Annotations[routerCertHashAnnotation]
+	updated.Spec.Template.Spec.Containers[0].Env = expected.Spec.Template.Spec.Containers[0].Env
+	updated.Spec.Template.Spec.Containers[0].LivenessProbe.Handler.HTTPGet.Host = expected.Spec.Template.Spec.Containers[0].LivenessProbe.Handler.HTTPGet.Host
+	updated.Spec.Template.Spec.Containers[0].ReadinessProbe.Handler.HTTPGet.Host = expected.Spec.Template.Spec.Containers[0].ReadinessProbe.Handler.HTTPGet.Host
 	replicas := int32(1)
 	if expected.Spec.Replicas != nil {
 		replicas = *expected.Spec.Replicas
 	}
 	updated.Spec.Replicas = &replicas
 	return true, updated
-}
\ No newline at end of file
+}
+
+// envValue returns the value of the named env var in envs, or "" if it is
+// not present.
+func envValue(envs []corev1.EnvVar, name string) string {
+	for _, e := range envs {
+		if e.Name == name {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+// ipFamilyModeEnvVars returns the ROUTER_IP_V4_V6_MODE and
+// ROUTER_LISTEN_ADDR env vars that tell the router image which IP
+// family/families to bind HAProxy to, based on policy. RequireDualStack
+// and PreferDualStack both need HAProxy to bind both families; the
+// haproxy-config template reads ROUTER_IP_V4_V6_MODE to decide whether to
+// emit one bind line or one per family.
+func ipFamilyModeEnvVars(policy ingressv1alpha1.IPFamilyPolicyType) []corev1.EnvVar {
+	mode := "v4"
+	listenAddr := "0.0.0.0"
+	switch policy {
+	case ingressv1alpha1.IPFamilyPolicyIPv6:
+		mode = "v6"
+		listenAddr = "[::]"
+	case ingressv1alpha1.IPFamilyPolicyPreferDualStack, ingressv1alpha1.IPFamilyPolicyRequireDualStack:
+		mode = "v4v6"
+		listenAddr = "[::]"
+	}
+	return []corev1.EnvVar{
+		{Name: "ROUTER_IP_V4_V6_MODE", Value: mode},
+		{Name: "ROUTER_LISTEN_ADDR", Value: listenAddr},
+	}
+}
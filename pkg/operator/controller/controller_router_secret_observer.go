@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"hash"
+
+	ingressv1alpha1 "github.com/openshift/cluster-ingress-operator/pkg/apis/ingress/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// addRouterSecretWatch registers a watch on Secrets with c so that a
+// rotation of a secret a router deployment depends on -- the default
+// certificate secret named by a ClusterIngress's DefaultCertificateSecret,
+// or one of the well-known router-metrics-certs-* / router-stats-* secrets
+// -- requeues the owning ClusterIngress(es) for reconciliation instead of
+// waiting for something unrelated to trigger the next reconcile. This
+// mirrors cluster-authentication-operator's router secret observer, which
+// watches its own observed secrets the same way.
+func addRouterSecretWatch(c controller.Controller, cl client.Client) error {
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(
+		func(obj client.Object) []reconcile.Request {
+			return routerSecretToClusterIngressRequests(cl, obj)
+		},
+	))
+}
+
+// routerSecretToClusterIngressRequests maps a Secret to the
+// ClusterIngress(es) whose router deployment observes it: any
+// ClusterIngress named by the "router-<suffix>-<name>" convention
+// (router-metrics-certs, router-stats), plus any ClusterIngress whose
+// DefaultCertificateSecret explicitly names this secret.
+//
+// ClusterIngress is cluster-scoped -- routerDeploymentName and every other
+// lookup in this package key off ci.Name alone, never a namespace -- so
+// ClusterIngresses are listed without a namespace filter, and the emitted
+// requests carry no Namespace either.
+func routerSecretToClusterIngressRequests(cl client.Client, obj client.Object) []reconcile.Request {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return nil
+	}
+
+	list := &ingressv1alpha1.ClusterIngressList{}
+	if err := cl.List(context.TODO(), list); err != nil {
+		log.Error(err, "failed to list clusteringresses for secret watch", "secret", secret.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range list.Items {
+		ci := &list.Items[i]
+		switch secret.Name {
+		case fmt.Sprintf("router-metrics-certs-%s", ci.Name), fmt.Sprintf("router-stats-%s", ci.Name):
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ci.Name}})
+			continue
+		}
+		if ci.Spec.DefaultCertificateSecret != nil && *ci.Spec.DefaultCertificateSecret == secret.Name {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ci.Name}})
+		}
+	}
+	return requests
+}
+
+// observeRouterCertSecrets reads the secrets that desired's router
+// deployment depends on -- the default certificate secret named by
+// desired's first volume, the router-metrics-certs-<name> secret, and the
+// router-stats-<name> secret -- and stamps a SHA256 hash of their combined
+// content onto desired's pod template as the routerCertHashAnnotation.
+// This lets deploymentConfigChanged detect an in-place secret rotation
+// (cert-manager renewal, a manual update, a stats credential change) that
+// leaves the secret's name unchanged but replaces its contents, the same
+// way cluster-authentication-operator's router secret observer rolls out
+// content changes to its own observed secrets. addRouterSecretWatch is
+// what actually notices a rotation as it happens; this function is what
+// computes the hash the next reconcile picks up.
+//
+// The two cert secrets are additionally parsed with tls.X509KeyPair to
+// confirm they hold a usable certificate/key pair. A secret that fails to
+// parse marks ci as Degraded rather than silently deploying a router with
+// a broken TLS configuration.
+func (r *reconciler) observeRouterCertSecrets(ci *ingressv1alpha1.ClusterIngress, desired *appsv1.Deployment) error {
+	h := sha256.New()
+
+	defaultCertSecretName := desired.Spec.Template.Spec.Volumes[0].Secret.SecretName
+	if err := r.hashCertSecret(desired.Namespace, defaultCertSecretName, h); err != nil {
+		r.setRouterSecretDegraded(ci, err)
+		return err
+	}
+
+	metricsCertsSecretName := fmt.Sprintf("router-metrics-certs-%s", ci.Name)
+	if err := r.hashCertSecret(desired.Namespace, metricsCertsSecretName, h); err != nil {
+		r.setRouterSecretDegraded(ci, err)
+		return err
+	}
+
+	statsSecretName := fmt.Sprintf("router-stats-%s", ci.Name)
+	if err := r.hashOpaqueSecret(desired.Namespace, statsSecretName, h); err != nil {
+		r.setRouterSecretDegraded(ci, err)
+		return err
+	}
+
+	if desired.Spec.Template.Annotations == nil {
+		desired.Spec.Template.Annotations = map[string]string{}
+	}
+	desired.Spec.Template.Annotations[routerCertHashAnnotation] = hex.EncodeToString(h.Sum(nil))
+	return nil
+}
+
+// hashCertSecret fetches the named secret, validates that it contains a
+// usable TLS keypair, and writes the keypair's bytes into h.
+func (r *reconciler) hashCertSecret(namespace, name string, h hash.Hash) error {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	if err := r.Client.Get(context.TODO(), key, secret); err != nil {
+		return fmt.Errorf("failed to get secret %s: %v", key, err)
+	}
+
+	cert := secret.Data["tls.crt"]
+	privateKey := secret.Data["tls.key"]
+	if _, err := tls.X509KeyPair(cert, privateKey); err != nil {
+		return fmt.Errorf("secret %s does not contain a usable TLS keypair: %v", key, err)
+	}
+
+	if _, err := h.Write(cert); err != nil {
+		return err
+	}
+	if _, err := h.Write(privateKey); err != nil {
+		return err
+	}
+	return nil
+}
+
+// hashOpaqueSecret fetches the named secret and writes its data values into
+// h, in a stable key order, without any keypair validation. It is used for
+// secrets that hold plain credentials (router-stats-<name>'s
+// statsUsername/statsPassword) rather than a TLS certificate.
+func (r *reconciler) hashOpaqueSecret(namespace, name string, h hash.Hash) error {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	if err := r.Client.Get(context.TODO(), key, secret); err != nil {
+		return fmt.Errorf("failed to get secret %s: %v", key, err)
+	}
+
+	for _, k := range []string{"statsUsername", "statsPassword"} {
+		if _, err := h.Write(secret.Data[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setRouterSecretDegraded records a Degraded condition on ci's status
+// explaining that one of its router deployment's secrets (a TLS secret or
+// the stats secret) could not be observed, so the failure is visible on the
+// ClusterIngress itself and not
+// just in the operator's logs.
+func (r *reconciler) setRouterSecretDegraded(ci *ingressv1alpha1.ClusterIngress, cause error) {
+	condition := ingressv1alpha1.ClusterIngressCondition{
+		Type:               ingressv1alpha1.ClusterIngressDegraded,
+		Status:             corev1.ConditionTrue,
+		Reason:             "RouterSecretInvalid",
+		Message:            cause.Error(),
+		LastTransitionTime: metav1.Now(),
+	}
+
+	updated := ci.DeepCopy()
+	found := false
+	for i := range updated.Status.Conditions {
+		if updated.Status.Conditions[i].Type == ingressv1alpha1.ClusterIngressDegraded {
+			updated.Status.Conditions[i] = condition
+			found = true
+			break
+		}
+	}
+	if !found {
+		updated.Status.Conditions = append(updated.Status.Conditions, condition)
+	}
+
+	if err := r.Client.Status().Update(context.TODO(), updated); err != nil {
+		log.Error(err, "failed to set degraded condition on clusteringress", "name", ci.Name)
+	}
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"testing"
+
+	ingressv1alpha1 "github.com/openshift/cluster-ingress-operator/pkg/apis/ingress/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRouterSecretToClusterIngressRequests(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add corev1 to scheme: %v", err)
+	}
+	if err := ingressv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add ingressv1alpha1 to scheme: %v", err)
+	}
+
+	defaultCertSecretName := "custom-cert"
+	ingresses := []runtime.Object{
+		&ingressv1alpha1.ClusterIngress{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		},
+		&ingressv1alpha1.ClusterIngress{
+			ObjectMeta: metav1.ObjectMeta{Name: "other"},
+			Spec:       ingressv1alpha1.ClusterIngressSpec{DefaultCertificateSecret: &defaultCertSecretName},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		secretName string
+		want       []string
+	}{
+		{name: "metrics certs secret for default", secretName: "router-metrics-certs-default", want: []string{"default"}},
+		{name: "stats secret for default", secretName: "router-stats-default", want: []string{"default"}},
+		{name: "explicit default certificate secret", secretName: "custom-cert", want: []string{"other"}},
+		{name: "unrelated secret", secretName: "some-other-secret", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(ingresses...).Build()
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: tt.secretName, Namespace: "openshift-ingress"}}
+
+			requests := routerSecretToClusterIngressRequests(cl, secret)
+
+			var got []string
+			for _, req := range requests {
+				if len(req.Namespace) != 0 {
+					t.Errorf("expected request for %q to have no namespace, got %q", req.Name, req.Namespace)
+				}
+				got = append(got, req.Name)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got requests %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got requests %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}